@@ -8,13 +8,29 @@ import (
 )
 
 type command struct {
-	stdout io.Writer
-	stderr io.Writer
+	stdout      io.Writer
+	stderr      io.Writer
+	passthrough bool
+}
+
+// Option configures a Capture command.
+type Option func(*command)
+
+// WithPassthrough controls whether captured data is also forwarded to the
+// downstream stdout/stderr writers supplied by the pipeline executor,
+// letting Capture act as a mid-pipeline tee instead of a terminal sink.
+// It defaults to true; pass false to restore the original sink-only
+// behavior where downstream writers never see the data.
+func WithPassthrough(enabled bool) Option {
+	return func(c *command) {
+		c.passthrough = enabled
+	}
 }
 
 // Capture creates a command that captures stdin to the provided writers.
-// This is useful as a pipeline sink when you want to capture output instead of
-// writing to os.Stdout/os.Stderr.
+// By default it also forwards stdin downstream, so it can be placed mid
+// pipeline without terminating it; use WithPassthrough(false) to restore
+// the original sink-only behavior.
 //
 // Example:
 //
@@ -26,22 +42,35 @@ type command struct {
 //	)
 //	gloo.MustRun(pipeline)
 //	// Now stdout and stderr contain the captured output
-func Capture(stdout, stderr io.Writer) gloo.Command {
-	return command{
-		stdout: stdout,
-		stderr: stderr,
+func Capture(stdout, stderr io.Writer, opts ...Option) gloo.Command {
+	c := command{
+		stdout:      stdout,
+		stderr:      stderr,
+		passthrough: true,
 	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
 }
 
 func (c command) Executor() gloo.CommandExecutor {
-	return func(ctx context.Context, stdin io.Reader, _, _ io.Writer) error {
+	return func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer) error {
+		out := c.stdout
+		if c.passthrough && stdout != nil {
+			out = io.MultiWriter(c.stdout, stdout)
+		}
+
 		// Copy stdin to the provided stdout writer
-		_, err := io.Copy(c.stdout, stdin)
+		_, err := io.Copy(out, stdin)
 		if err != nil {
 			// If there's an error copying, write it to our stderr
 			if c.stderr != nil {
 				c.stderr.Write([]byte("capture: " + err.Error() + "\n"))
 			}
+			if c.passthrough && stderr != nil {
+				stderr.Write([]byte("capture: " + err.Error() + "\n"))
+			}
 			return err
 		}
 		return nil
@@ -0,0 +1,44 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCapturePassthroughDefaultOn(t *testing.T) {
+	var captureOut, captureErr bytes.Buffer
+	var downstreamOut, downstreamErr bytes.Buffer
+
+	executor := Capture(&captureOut, &captureErr).Executor()
+	err := executor(context.Background(), strings.NewReader("hello"), &downstreamOut, &downstreamErr)
+	if err != nil {
+		t.Fatalf("Executor: %v", err)
+	}
+
+	if got, want := captureOut.String(), "hello"; got != want {
+		t.Errorf("captureOut = %q, want %q", got, want)
+	}
+	if got, want := downstreamOut.String(), "hello"; got != want {
+		t.Errorf("downstreamOut = %q, want %q (should be forwarded by default)", got, want)
+	}
+}
+
+func TestCaptureWithPassthroughFalse(t *testing.T) {
+	var captureOut, captureErr bytes.Buffer
+	var downstreamOut, downstreamErr bytes.Buffer
+
+	executor := Capture(&captureOut, &captureErr, WithPassthrough(false)).Executor()
+	err := executor(context.Background(), strings.NewReader("hello"), &downstreamOut, &downstreamErr)
+	if err != nil {
+		t.Fatalf("Executor: %v", err)
+	}
+
+	if got, want := captureOut.String(), "hello"; got != want {
+		t.Errorf("captureOut = %q, want %q", got, want)
+	}
+	if got := downstreamOut.String(); got != "" {
+		t.Errorf("downstreamOut = %q, want empty (passthrough disabled)", got)
+	}
+}
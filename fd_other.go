@@ -0,0 +1,33 @@
+//go:build !unix
+
+package command
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	gloo "github.com/gloo-foo/framework"
+)
+
+// ErrFDCaptureUnsupported is returned by CaptureFD's executor on platforms
+// that don't support POSIX-style fd duplication.
+var ErrFDCaptureUnsupported = errors.New("capture: CaptureFD is not supported on this platform")
+
+type fdCommand struct {
+	fd int
+	w  io.Writer
+}
+
+// CaptureFD creates a command that redirects the OS-level file descriptor fd
+// into w for the lifetime of the executor. It is only implemented on Unix
+// platforms; elsewhere its executor returns ErrFDCaptureUnsupported.
+func CaptureFD(fd int, w io.Writer) gloo.Command {
+	return fdCommand{fd: fd, w: w}
+}
+
+func (c fdCommand) Executor() gloo.CommandExecutor {
+	return func(ctx context.Context, stdin io.Reader, _, _ io.Writer) error {
+		return ErrFDCaptureUnsupported
+	}
+}
@@ -0,0 +1,79 @@
+//go:build unix
+
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+
+	gloo "github.com/gloo-foo/framework"
+)
+
+// fdMu serializes fd redirection: Dup2 onto a well-known fd like 1 or 2 is
+// process-global, so only one CaptureFD executor may be redirecting a given
+// fd at a time.
+var fdMu sync.Mutex
+
+type fdCommand struct {
+	fd int
+	w  io.Writer
+}
+
+// CaptureFD creates a command that redirects the OS-level file descriptor fd
+// (typically 1 for stdout or 2 for stderr) into w for the lifetime of the
+// executor, then restores it. Unlike Capture, this observes writes made
+// directly to the file descriptor, including by subprocesses spawned inside
+// the pipeline that bypass Go's io.Writer chain entirely.
+func CaptureFD(fd int, w io.Writer) gloo.Command {
+	return fdCommand{fd: fd, w: w}
+}
+
+func (c fdCommand) Executor() gloo.CommandExecutor {
+	return func(ctx context.Context, stdin io.Reader, _, _ io.Writer) error {
+		fdMu.Lock()
+		defer fdMu.Unlock()
+
+		saved, err := syscall.Dup(c.fd)
+		if err != nil {
+			return err
+		}
+		savedFile := os.NewFile(uintptr(saved), "capture-saved-fd")
+		defer savedFile.Close()
+
+		pr, pw, err := os.Pipe()
+		if err != nil {
+			return err
+		}
+
+		if err := syscall.Dup2(int(pw.Fd()), c.fd); err != nil {
+			pr.Close()
+			pw.Close()
+			return err
+		}
+
+		done := make(chan error, 1)
+		go func() {
+			_, copyErr := io.Copy(io.MultiWriter(savedFile, c.w), pr)
+			pr.Close()
+			done <- copyErr
+		}()
+
+		_, err = io.Copy(io.Discard, stdin)
+
+		if restoreErr := syscall.Dup2(saved, c.fd); restoreErr != nil {
+			// fd is left pointing at the pipe write end we're about to
+			// close, so surface this rather than silently leaving the
+			// caller's fd (e.g. its real stdout) broken.
+			err = errors.Join(err, fmt.Errorf("capture: restoring fd %d: %w", c.fd, restoreErr))
+		}
+		pw.Close()
+		<-done
+
+		return err
+	}
+}
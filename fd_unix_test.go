@@ -0,0 +1,99 @@
+//go:build unix
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCaptureFDRedirectsAndRestores(t *testing.T) {
+	dir := t.TempDir()
+	orig, err := os.Create(filepath.Join(dir, "orig"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	defer orig.Close()
+	fd := int(orig.Fd())
+
+	var captured bytes.Buffer
+	stdinR, stdinW := io.Pipe()
+
+	done := make(chan error, 1)
+	go func() {
+		executor := fdCommand{fd: fd, w: &captured}.Executor()
+		done <- executor(context.Background(), stdinR, nil, nil)
+	}()
+
+	// Give the executor time to Dup2 the pipe over fd before we simulate a
+	// subprocess writing directly to it.
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := syscall.Write(fd, []byte("redirected\n")); err != nil {
+		t.Fatalf("Write to redirected fd: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	stdinW.Close()
+
+	if err := <-done; err != nil {
+		t.Fatalf("Executor: %v", err)
+	}
+
+	if got, want := captured.String(), "redirected\n"; got != want {
+		t.Errorf("captured = %q, want %q", got, want)
+	}
+
+	// The original fd should have mirrored the same bytes while redirected.
+	origData, err := os.ReadFile(orig.Name())
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if got, want := string(origData), "redirected\n"; got != want {
+		t.Errorf("original fd contents = %q, want %q (mirrored)", got, want)
+	}
+
+	// After the executor returns, fd must be restored: a direct write goes
+	// to the original file and is no longer observed by the capture writer.
+	if _, err := syscall.Write(fd, []byte("after\n")); err != nil {
+		t.Fatalf("Write after restore: %v", err)
+	}
+	origData, err = os.ReadFile(orig.Name())
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if got, want := string(origData), "redirected\nafter\n"; got != want {
+		t.Errorf("original fd contents after restore = %q, want %q", got, want)
+	}
+	if got, want := captured.String(), "redirected\n"; got != want {
+		t.Errorf("captured after restore = %q, want %q (unchanged)", got, want)
+	}
+}
+
+func TestCaptureFDInvalidFDReturnsErrorAndUnlocks(t *testing.T) {
+	var captured bytes.Buffer
+	executor := fdCommand{fd: -1, w: &captured}.Executor()
+
+	if err := executor(context.Background(), bytes.NewReader(nil), nil, nil); err == nil {
+		t.Fatal("expected an error duplicating an invalid fd, got nil")
+	}
+
+	// A prior failure must not leave fdMu locked; this would deadlock
+	// otherwise.
+	done := make(chan struct{})
+	go func() {
+		executor(context.Background(), bytes.NewReader(nil), nil, nil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("fdMu appears to still be locked after a failed Executor call")
+	}
+}
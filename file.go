@@ -0,0 +1,258 @@
+package command
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	gloo "github.com/gloo-foo/framework"
+)
+
+type fileCommand struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+	bufferSize int
+}
+
+// FileOption configures a File command.
+type FileOption func(*fileCommand)
+
+// WithMaxSize rolls the capture file over once it reaches bytes in size. A
+// value of 0 (the default) disables size-based rollover.
+func WithMaxSize(bytes int64) FileOption {
+	return func(c *fileCommand) {
+		c.maxSize = bytes
+	}
+}
+
+// WithMaxAge rolls the capture file over once it has been open longer than
+// d. A value of 0 (the default) disables age-based rollover.
+func WithMaxAge(d time.Duration) FileOption {
+	return func(c *fileCommand) {
+		c.maxAge = d
+	}
+}
+
+// WithMaxBackups limits how many rolled-over segments are kept; the oldest
+// are removed once the limit is exceeded. A value of 0 (the default) keeps
+// all segments.
+func WithMaxBackups(n int) FileOption {
+	return func(c *fileCommand) {
+		c.maxBackups = n
+	}
+}
+
+// WithCompress gzips rolled-over segments.
+func WithCompress(enabled bool) FileOption {
+	return func(c *fileCommand) {
+		c.compress = enabled
+	}
+}
+
+// WithBufferSize sets the size of the buffer used to read from stdin before
+// it's written to the current segment.
+func WithBufferSize(n int) FileOption {
+	return func(c *fileCommand) {
+		c.bufferSize = n
+	}
+}
+
+// File creates a command that writes captured stdin to path, rolling over
+// to a new segment when the configured size or age limit is reached. This
+// lets a long-running capture persist safely to disk without the unbounded
+// memory growth of the in-memory Capture sink.
+func File(path string, opts ...FileOption) gloo.Command {
+	c := fileCommand{
+		path:       path,
+		bufferSize: 32 * 1024,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+func (c fileCommand) Executor() gloo.CommandExecutor {
+	return func(ctx context.Context, stdin io.Reader, _, _ io.Writer) error {
+		w := &rotatingWriter{cmd: c}
+		defer w.Close()
+
+		buf := make([]byte, c.bufferSize)
+		for {
+			n, readErr := stdin.Read(buf)
+			if n > 0 {
+				if _, err := w.Write(buf[:n]); err != nil {
+					return err
+				}
+			}
+			if readErr == io.EOF {
+				return nil
+			}
+			if readErr != nil {
+				return readErr
+			}
+		}
+	}
+}
+
+// rotatingWriter writes to path, transparently rolling over to a new
+// segment when the configured size or age limit is exceeded.
+type rotatingWriter struct {
+	cmd      fileCommand
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// Write writes p to the current segment, rotating to a new segment as
+// needed. A single call may span several segments: p is written in chunks
+// no larger than what's left of the current segment's maxSize budget, so
+// rollover is driven by how much has actually been written to the segment,
+// not by the size of any one Write call (which, via the bufferSize-sized
+// reads in File's executor, can otherwise be far larger than maxSize).
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		if w.file == nil {
+			if err := w.open(); err != nil {
+				return written, err
+			}
+		}
+		if w.shouldRotate() {
+			if err := w.rotate(); err != nil {
+				return written, err
+			}
+			continue
+		}
+
+		chunk := p
+		if w.cmd.maxSize > 0 {
+			if remaining := w.cmd.maxSize - w.size; int64(len(chunk)) > remaining {
+				chunk = chunk[:remaining]
+			}
+		}
+
+		n, err := w.file.Write(chunk)
+		w.size += int64(n)
+		written += n
+		p = p[n:]
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+func (w *rotatingWriter) open() error {
+	f, err := os.OpenFile(w.cmd.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.file = f
+	w.size = info.Size()
+	w.openedAt = time.Now()
+	return nil
+}
+
+// shouldRotate reports whether the current segment is already full or has
+// been open longer than maxAge.
+func (w *rotatingWriter) shouldRotate() bool {
+	if w.cmd.maxSize > 0 && w.size >= w.cmd.maxSize {
+		return true
+	}
+	if w.cmd.maxAge > 0 && time.Since(w.openedAt) > w.cmd.maxAge {
+		return true
+	}
+	return false
+}
+
+func (w *rotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	w.file = nil
+
+	backup := fmt.Sprintf("%s.%s", w.cmd.path, time.Now().Format("20060102T150405.000000000"))
+	if err := os.Rename(w.cmd.path, backup); err != nil {
+		return err
+	}
+	if w.cmd.compress {
+		if err := gzipFile(backup); err != nil {
+			return err
+		}
+		backup += ".gz"
+	}
+
+	if w.cmd.maxBackups > 0 {
+		if err := pruneBackups(w.cmd.path, w.cmd.maxBackups); err != nil {
+			return err
+		}
+	}
+
+	return w.open()
+}
+
+func (w *rotatingWriter) Close() error {
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Close()
+}
+
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	gw := gzip.NewWriter(dst)
+	if _, err := io.Copy(gw, src); err != nil {
+		gw.Close()
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// pruneBackups removes the oldest rolled-over segments of path once there
+// are more than maxBackups of them. Segment names are timestamp-suffixed,
+// so lexical order is chronological order.
+func pruneBackups(path string, maxBackups int) error {
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return err
+	}
+	sort.Strings(matches)
+	if len(matches) <= maxBackups {
+		return nil
+	}
+	for _, old := range matches[:len(matches)-maxBackups] {
+		if err := os.Remove(old); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package command
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRotatingWriterMaxSizeSplitsAcrossSegments(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "capture.log")
+
+	w := &rotatingWriter{cmd: fileCommand{path: path, maxSize: 10}}
+	defer w.Close()
+
+	// A single large write, far bigger than maxSize, must still produce
+	// segments no larger than maxSize rather than one oversized segment.
+	if _, err := w.Write([]byte("0123456789abcdefghij")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat current segment: %v", err)
+	}
+	if info.Size() > 10 {
+		t.Errorf("current segment size = %d, want <= 10", info.Size())
+	}
+
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) == 0 {
+		t.Fatalf("expected at least one rolled-over segment, found none")
+	}
+	for _, m := range matches {
+		info, err := os.Stat(m)
+		if err != nil {
+			t.Fatalf("Stat %s: %v", m, err)
+		}
+		if info.Size() > 10 {
+			t.Errorf("segment %s size = %d, want <= 10", m, info.Size())
+		}
+	}
+}
+
+func TestRotatingWriterMaxSizeSmallerThanBufferSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "capture.log")
+
+	c := fileCommand{path: path, maxSize: 4, bufferSize: 32 * 1024}
+	w := &rotatingWriter{cmd: c}
+	defer w.Close()
+
+	// Simulate File's executor feeding a bufferSize-sized read even though
+	// maxSize is much smaller: rollover must be driven by maxSize, not by
+	// the size of this one Write call.
+	if _, err := w.Write([]byte("aaaabbbbccccdddd")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Size() != 4 {
+		t.Errorf("current segment size = %d, want 4", info.Size())
+	}
+
+	matches, _ := filepath.Glob(path + ".*")
+	if len(matches) != 3 {
+		t.Errorf("got %d rolled-over segments, want 3", len(matches))
+	}
+}
+
+func TestRotatingWriterMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "capture.log")
+
+	w := &rotatingWriter{cmd: fileCommand{path: path, maxAge: time.Millisecond}}
+	defer w.Close()
+
+	if _, err := w.Write([]byte("first")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := w.Write([]byte("second")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	matches, _ := filepath.Glob(path + ".*")
+	if len(matches) != 1 {
+		t.Fatalf("got %d rolled-over segments, want 1", len(matches))
+	}
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "first" {
+		t.Errorf("rolled-over segment = %q, want %q", data, "first")
+	}
+}
+
+func TestRotatingWriterCompress(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "capture.log")
+
+	w := &rotatingWriter{cmd: fileCommand{path: path, maxSize: 4, compress: true}}
+	defer w.Close()
+
+	if _, err := w.Write([]byte("aaaabbbb")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	matches, _ := filepath.Glob(path + ".*.gz")
+	if len(matches) != 1 {
+		t.Fatalf("got %d compressed segments, want 1", len(matches))
+	}
+
+	f, err := os.Open(matches[0])
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	defer gr.Close()
+
+	data, err := io.ReadAll(gr)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(data) != "aaaa" {
+		t.Errorf("decompressed segment = %q, want %q", data, "aaaa")
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "capture.log")
+
+	for _, suffix := range []string{"1", "2", "3", "4"} {
+		if err := os.WriteFile(path+"."+suffix, []byte("x"), 0o644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	if err := pruneBackups(path, 2); err != nil {
+		t.Fatalf("pruneBackups: %v", err)
+	}
+
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("got %d backups after pruning, want 2", len(matches))
+	}
+	for _, m := range matches {
+		if filepath.Base(m) == filepath.Base(path)+".1" || filepath.Base(m) == filepath.Base(path)+".2" {
+			t.Errorf("oldest backup %s should have been pruned", m)
+		}
+	}
+}
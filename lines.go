@@ -0,0 +1,139 @@
+package command
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	gloo "github.com/gloo-foo/framework"
+)
+
+type linesCommand struct {
+	onLine      func(string)
+	maxLineSize int
+	history     *LineHistory
+	separator   byte
+}
+
+// LineOption configures a Lines command.
+type LineOption func(*linesCommand)
+
+// WithMaxLineSize sets the largest line bufio.Scanner will buffer. It
+// defaults to bufio.MaxScanTokenSize.
+func WithMaxLineSize(n int) LineOption {
+	return func(c *linesCommand) {
+		c.maxLineSize = n
+	}
+}
+
+// LineHistory is a bounded, concurrency-safe ring buffer of the most
+// recently observed lines. Pass one to WithHistory to have Lines populate
+// it as input streams through; it stays readable via Lines after the
+// executor has finished, which is useful for tailing long-running
+// pipelines without unbounded memory growth.
+type LineHistory struct {
+	mu    sync.Mutex
+	max   int
+	lines []string
+}
+
+// NewLineHistory creates a LineHistory that retains at most the last max
+// lines.
+func NewLineHistory(max int) *LineHistory {
+	return &LineHistory{max: max}
+}
+
+func (h *LineHistory) push(line string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lines = append(h.lines, line)
+	if len(h.lines) > h.max {
+		h.lines = h.lines[len(h.lines)-h.max:]
+	}
+}
+
+// Lines returns a copy of the currently retained lines, oldest first.
+func (h *LineHistory) Lines() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.lines...)
+}
+
+// WithHistory records up to h's configured bound of the most recently
+// streamed lines into h.
+func WithHistory(h *LineHistory) LineOption {
+	return func(c *linesCommand) {
+		c.history = h
+	}
+}
+
+// WithSeparator overrides the line separator byte, e.g. 0 for NUL-delimited
+// input. It defaults to '\n'.
+func WithSeparator(sep byte) LineOption {
+	return func(c *linesCommand) {
+		c.separator = sep
+	}
+}
+
+// Lines creates a command that scans stdin line by line, invoking onLine for
+// each line as it streams through, rather than buffering the whole input.
+// This makes capture usable as a streaming observation point, e.g. for
+// progress reporting. Use WithHistory to additionally retain the most
+// recent lines for later inspection.
+func Lines(onLine func(string), opts ...LineOption) gloo.Command {
+	c := linesCommand{
+		onLine:    onLine,
+		separator: '\n',
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+func (c linesCommand) Executor() gloo.CommandExecutor {
+	return func(ctx context.Context, stdin io.Reader, _, _ io.Writer) error {
+		scanner := bufio.NewScanner(stdin)
+		if c.separator != '\n' {
+			scanner.Split(splitOn(c.separator))
+		}
+		if c.maxLineSize > 0 {
+			// The scanner never enforces a limit below its buffer's initial
+			// capacity, so start no larger than maxLineSize.
+			initial := 64 * 1024
+			if c.maxLineSize < initial {
+				initial = c.maxLineSize
+			}
+			scanner.Buffer(make([]byte, 0, initial), c.maxLineSize)
+		}
+
+		for scanner.Scan() {
+			line := scanner.Text()
+			if c.onLine != nil {
+				c.onLine(line)
+			}
+			if c.history != nil {
+				c.history.push(line)
+			}
+		}
+		return scanner.Err()
+	}
+}
+
+// splitOn returns a bufio.SplitFunc that splits on sep instead of '\n'.
+func splitOn(sep byte) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+		if i := bytes.IndexByte(data, sep); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		if atEOF {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
@@ -0,0 +1,30 @@
+package command
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLineHistoryBounds(t *testing.T) {
+	h := NewLineHistory(3)
+	for _, line := range []string{"a", "b", "c", "d", "e"} {
+		h.push(line)
+	}
+
+	got := h.Lines()
+	want := []string{"c", "d", "e"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines() = %v, want %v", got, want)
+	}
+}
+
+func TestLineHistoryUnderCapacity(t *testing.T) {
+	h := NewLineHistory(5)
+	h.push("only")
+
+	got := h.Lines()
+	want := []string{"only"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines() = %v, want %v", got, want)
+	}
+}
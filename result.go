@@ -0,0 +1,197 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	gloo "github.com/gloo-foo/framework"
+)
+
+// Result is the captured output of a pipeline run, with chainable
+// post-capture processing inspired by bitfield/script.
+type Result struct {
+	stdout []byte
+	stderr []byte
+	err    error
+}
+
+// Run executes pipeline with a capturing sink installed at its end and
+// returns a *Result for inspecting what it produced. This makes the package
+// usable as an ergonomic entry point for pipeline consumers, rather than a
+// low-level sink they have to wire bytes.Buffers into themselves.
+//
+// Example:
+//
+//	result := capture.Run(gloo.Pipe(
+//	    grep.Grep("ERROR"),
+//	    sort.Sort(),
+//	))
+//	fmt.Println(result.CountLines())
+func Run(pipeline gloo.Pipeline) *Result {
+	var stdout, stderr bytes.Buffer
+	// append(pipeline, ...) in place would risk overwriting elements of the
+	// caller's backing array if it has spare capacity; copy defensively so
+	// Run never mutates its input.
+	full := append(append(gloo.Pipeline{}, pipeline...), Capture(&stdout, &stderr, WithPassthrough(false)))
+	err := gloo.Run(context.Background(), full)
+	return &Result{stdout: stdout.Bytes(), stderr: stderr.Bytes(), err: err}
+}
+
+// String returns the captured stdout as a string.
+func (r *Result) String() string {
+	return string(r.stdout)
+}
+
+// Bytes returns the captured stdout.
+func (r *Result) Bytes() []byte {
+	return r.stdout
+}
+
+// Lines splits the captured stdout into lines, dropping the trailing empty
+// line left by a final newline.
+func (r *Result) Lines() []string {
+	s := strings.TrimSuffix(string(r.stdout), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// CountLines returns the number of lines in the captured stdout.
+func (r *Result) CountLines() int {
+	return len(r.Lines())
+}
+
+// Match returns a Result whose stdout contains only the lines that contain
+// substr.
+func (r *Result) Match(substr string) *Result {
+	return r.filterLines(func(line string) bool {
+		return strings.Contains(line, substr)
+	})
+}
+
+// MatchRegexp returns a Result whose stdout contains only the lines that
+// match re.
+func (r *Result) MatchRegexp(re *regexp.Regexp) *Result {
+	return r.filterLines(re.MatchString)
+}
+
+func (r *Result) filterLines(keep func(string) bool) *Result {
+	if r.err != nil {
+		return r
+	}
+	var kept []string
+	for _, line := range r.Lines() {
+		if keep(line) {
+			kept = append(kept, line)
+		}
+	}
+	return r.withLines(kept)
+}
+
+// First returns a Result whose stdout contains only the first n lines.
+func (r *Result) First(n int) *Result {
+	if r.err != nil {
+		return r
+	}
+	lines := r.Lines()
+	if n < 0 {
+		n = 0
+	}
+	if n > len(lines) {
+		n = len(lines)
+	}
+	return r.withLines(lines[:n])
+}
+
+// Last returns a Result whose stdout contains only the last n lines.
+func (r *Result) Last(n int) *Result {
+	if r.err != nil {
+		return r
+	}
+	lines := r.Lines()
+	if n < 0 {
+		n = 0
+	}
+	if n > len(lines) {
+		n = len(lines)
+	}
+	return r.withLines(lines[len(lines)-n:])
+}
+
+func (r *Result) withLines(lines []string) *Result {
+	if len(lines) == 0 {
+		return &Result{stderr: r.stderr}
+	}
+	return &Result{stdout: []byte(strings.Join(lines, "\n") + "\n"), stderr: r.stderr}
+}
+
+// Freq returns a Result whose stdout lists each distinct captured line once,
+// formatted as "<count> <line>" and sorted by descending count. Lines with
+// equal counts keep the order in which they first appeared.
+func (r *Result) Freq() *Result {
+	if r.err != nil {
+		return r
+	}
+	counts := map[string]int{}
+	var order []string
+	for _, line := range r.Lines() {
+		if counts[line] == 0 {
+			order = append(order, line)
+		}
+		counts[line]++
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+	var b strings.Builder
+	for _, line := range order {
+		fmt.Fprintf(&b, "%d %s\n", counts[line], line)
+	}
+	return &Result{stdout: []byte(b.String()), stderr: r.stderr}
+}
+
+// JQ pipes the captured stdout through the jq command-line tool with the
+// given query and returns the result. It requires jq to be installed.
+func (r *Result) JQ(query string) *Result {
+	if r.err != nil {
+		return r
+	}
+	cmd := exec.Command("jq", query)
+	cmd.Stdin = bytes.NewReader(r.stdout)
+	var out, errOut bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+	if err := cmd.Run(); err != nil {
+		return &Result{stderr: r.stderr, err: fmt.Errorf("capture: jq: %w: %s", err, errOut.String())}
+	}
+	return &Result{stdout: out.Bytes(), stderr: r.stderr}
+}
+
+// SHA256 returns the hex-encoded SHA-256 digest of the captured stdout.
+func (r *Result) SHA256() string {
+	sum := sha256.Sum256(r.stdout)
+	return hex.EncodeToString(sum[:])
+}
+
+// WriteFile writes the captured stdout to path.
+func (r *Result) WriteFile(path string) error {
+	if r.err != nil {
+		return r.err
+	}
+	return os.WriteFile(path, r.stdout, 0o644)
+}
+
+// Error returns any error produced while running the pipeline, or by a
+// subsequent transformation such as JQ.
+func (r *Result) Error() error {
+	return r.err
+}
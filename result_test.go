@@ -0,0 +1,72 @@
+package command
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	gloo "github.com/gloo-foo/framework"
+)
+
+func newTestResult(lines ...string) *Result {
+	r := &Result{}
+	return r.withLines(lines)
+}
+
+type fakeCommand struct {
+	executor gloo.CommandExecutor
+}
+
+func (f fakeCommand) Executor() gloo.CommandExecutor {
+	return f.executor
+}
+
+func TestRunDoesNotMutateInputPipeline(t *testing.T) {
+	noop := fakeCommand{executor: func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer) error {
+		return nil
+	}}
+
+	// Give the pipeline spare capacity so an in-place append would silently
+	// write Run's Capture sink into the caller's backing array.
+	pipeline := make(gloo.Pipeline, 1, 4)
+	pipeline[0] = noop
+
+	Run(pipeline)
+
+	if len(pipeline) != 1 {
+		t.Errorf("Run changed pipeline length: got %d, want 1", len(pipeline))
+	}
+	if revealed := pipeline[:cap(pipeline)]; revealed[1] != nil {
+		t.Errorf("Run wrote into the caller's spare capacity at index 1")
+	}
+}
+
+func TestResultFirstNegativeClamps(t *testing.T) {
+	r := newTestResult("a", "b", "c")
+
+	got := r.First(-1).Lines()
+	if got != nil {
+		t.Errorf("First(-1).Lines() = %v, want nil", got)
+	}
+}
+
+func TestResultLastNegativeClamps(t *testing.T) {
+	r := newTestResult("a", "b", "c")
+
+	got := r.Last(-1).Lines()
+	if got != nil {
+		t.Errorf("Last(-1).Lines() = %v, want nil", got)
+	}
+}
+
+func TestResultFirstLast(t *testing.T) {
+	r := newTestResult("a", "b", "c", "d")
+
+	if got, want := r.First(2).Lines(), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("First(2).Lines() = %v, want %v", got, want)
+	}
+	if got, want := r.Last(2).Lines(), []string{"c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Last(2).Lines() = %v, want %v", got, want)
+	}
+}
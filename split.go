@@ -0,0 +1,180 @@
+package command
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	gloo "github.com/gloo-foo/framework"
+)
+
+// Stream identifies which downstream writer a chunk of split data belongs
+// to.
+type Stream int
+
+const (
+	// StreamStdout routes a chunk to the stdout writer.
+	StreamStdout Stream = iota
+	// StreamStderr routes a chunk to the stderr writer.
+	StreamStderr
+)
+
+// frameHeaderSize is the size in bytes of a framed-input header: a 1-byte
+// stream id followed by a 4-byte big-endian payload length.
+const frameHeaderSize = 5
+
+// defaultMaxFrameSize bounds a framed-input payload length when
+// WithMaxFrameSize hasn't overridden it, so a corrupted or malicious length
+// header can't force an unbounded allocation.
+const defaultMaxFrameSize = bufio.MaxScanTokenSize
+
+type splitCommand struct {
+	stdout       io.Writer
+	stderr       io.Writer
+	classifier   func([]byte) Stream
+	framed       bool
+	maxFrameSize int
+}
+
+// SplitOption configures a Split command.
+type SplitOption func(*splitCommand)
+
+// WithFramedInput switches Split from newline-delimited scanning to reading
+// a length-prefixed framing protocol: each frame is a 1-byte stream id
+// followed by a 4-byte big-endian payload length and that many bytes of
+// payload. Use this when an earlier pipeline stage muxes stdout and stderr
+// into a single stream by tagging each write with a frame, rather than by
+// prefixing lines; pair it with FrameClassifier, which is called with just
+// the frame's id byte.
+func WithFramedInput() SplitOption {
+	return func(c *splitCommand) {
+		c.framed = true
+	}
+}
+
+// WithMaxFrameSize bounds the payload length a framed-input frame may
+// declare; a frame whose length header exceeds n makes the executor return
+// an error instead of allocating a buffer for it. It defaults to
+// bufio.MaxScanTokenSize, the same bound the line-based path's default
+// bufio.Scanner places on a line.
+func WithMaxFrameSize(n int) SplitOption {
+	return func(c *splitCommand) {
+		c.maxFrameSize = n
+	}
+}
+
+// Split creates a command that demultiplexes stdin into stdoutW and stderrW,
+// using classifier to decide which stream each chunk belongs to. By default
+// stdin is scanned line by line and classifier is called with each line; use
+// WithFramedInput to instead read a length-prefixed framing protocol. This is
+// useful when an earlier pipeline stage has merged stdout and stderr into a
+// single stream to preserve ordering, and a later stage needs to separate
+// them again.
+func Split(stdoutW, stderrW io.Writer, classifier func([]byte) Stream, opts ...SplitOption) gloo.Command {
+	c := splitCommand{
+		stdout:       stdoutW,
+		stderr:       stderrW,
+		classifier:   classifier,
+		maxFrameSize: defaultMaxFrameSize,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+func (c splitCommand) Executor() gloo.CommandExecutor {
+	return func(ctx context.Context, stdin io.Reader, _, _ io.Writer) error {
+		if c.framed {
+			return c.splitFrames(stdin)
+		}
+		return c.splitLines(stdin)
+	}
+}
+
+func (c splitCommand) splitLines(stdin io.Reader) error {
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if err := c.route(line, line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// splitFrames reads stdin as a sequence of frames, each a 1-byte stream id
+// followed by a 4-byte big-endian payload length and that many bytes of
+// payload, and routes each payload according to c.classifier(id byte).
+func (c splitCommand) splitFrames(stdin io.Reader) error {
+	r := bufio.NewReader(stdin)
+	header := make([]byte, frameHeaderSize)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		id := header[0:1]
+		length := binary.BigEndian.Uint32(header[1:])
+		if length > uint32(c.maxFrameSize) {
+			return fmt.Errorf("capture: framed input declared length %d, exceeds max frame size %d", length, c.maxFrameSize)
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return err
+		}
+
+		if err := c.route(id, payload); err != nil {
+			return err
+		}
+	}
+}
+
+// route writes payload to stdout or stderr according to
+// c.classifier(classify); a plain-line payload gets a trailing newline
+// restored since bufio.Scanner strips it, while a framed payload is written
+// verbatim.
+func (c splitCommand) route(classify, payload []byte) error {
+	w := c.stdout
+	if c.classifier(classify) == StreamStderr {
+		w = c.stderr
+	}
+	if w == nil {
+		return nil
+	}
+	if !c.framed {
+		payload = append(append([]byte{}, payload...), '\n')
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// PrefixClassifier returns a classifier that routes lines starting with
+// prefix to StreamStderr and everything else to StreamStdout.
+func PrefixClassifier(prefix string) func([]byte) Stream {
+	p := []byte(prefix)
+	return func(line []byte) Stream {
+		if bytes.HasPrefix(line, p) {
+			return StreamStderr
+		}
+		return StreamStdout
+	}
+}
+
+// FrameClassifier returns a classifier for use with WithFramedInput: it's
+// called with just the frame's 1-byte stream id (0 for stdout, 1 for
+// stderr). Any other id classifies as StreamStdout.
+func FrameClassifier() func([]byte) Stream {
+	return func(id []byte) Stream {
+		if len(id) > 0 && id[0] == byte(StreamStderr) {
+			return StreamStderr
+		}
+		return StreamStdout
+	}
+}
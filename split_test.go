@@ -0,0 +1,85 @@
+package command
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestSplitLinesPrefixClassifier(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	c := splitCommand{
+		stdout:     &stdout,
+		stderr:     &stderr,
+		classifier: PrefixClassifier("stderr:"),
+	}
+
+	if err := c.splitLines(bytes.NewBufferString("ok\nstderr:boom\nmore\n")); err != nil {
+		t.Fatalf("splitLines: %v", err)
+	}
+
+	if got, want := stdout.String(), "ok\nmore\n"; got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+	if got, want := stderr.String(), "stderr:boom\n"; got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+}
+
+func TestSplitFramedInput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	c := splitCommand{
+		stdout:       &stdout,
+		stderr:       &stderr,
+		classifier:   FrameClassifier(),
+		framed:       true,
+		maxFrameSize: defaultMaxFrameSize,
+	}
+
+	var in bytes.Buffer
+	writeFrame(&in, 0, []byte("hello"))
+	writeFrame(&in, 1, []byte("oops"))
+	writeFrame(&in, 0, []byte("world"))
+
+	if err := c.splitFrames(&in); err != nil {
+		t.Fatalf("splitFrames: %v", err)
+	}
+
+	if got, want := stdout.String(), "helloworld"; got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+	if got, want := stderr.String(), "oops"; got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+}
+
+func TestSplitFramedInputRejectsOversizedLength(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	c := splitCommand{
+		stdout:       &stdout,
+		stderr:       &stderr,
+		classifier:   FrameClassifier(),
+		framed:       true,
+		maxFrameSize: 4,
+	}
+
+	var in bytes.Buffer
+	// Declares a payload far larger than maxFrameSize and the actual bytes
+	// available; this must be rejected before any allocation is attempted.
+	var header [5]byte
+	binary.BigEndian.PutUint32(header[1:], 0xFFFFFFFF)
+	in.Write(header[:])
+
+	err := c.splitFrames(&in)
+	if err == nil {
+		t.Fatal("expected an error for a frame length exceeding maxFrameSize, got nil")
+	}
+}
+
+func writeFrame(buf *bytes.Buffer, id byte, payload []byte) {
+	buf.WriteByte(id)
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(payload)))
+	buf.Write(length[:])
+	buf.Write(payload)
+}